@@ -0,0 +1,128 @@
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// afxdpctl is a small operator CLI for talking to a running plugin's
+// log level control socket, e.g.:
+//
+//	afxdpctl log set cni=debug
+//	afxdpctl log set all=warning
+//	afxdpctl log get
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/intel/afxdp-plugins-for-kubernetes/pkg/logging"
+)
+
+func main() {
+	if len(os.Args) < 3 || os.Args[1] != "log" {
+		usage()
+		os.Exit(1)
+	}
+
+	socket := logging.DefaultControlSocket
+	if s := os.Getenv("AFXDP_LOG_SOCKET"); s != "" {
+		socket = s
+	}
+
+	client := controlClient(socket)
+
+	var err error
+	switch os.Args[2] {
+	case "set":
+		if len(os.Args) < 4 {
+			usage()
+			os.Exit(1)
+		}
+		err = runSet(client, os.Args[3])
+	case "get":
+		err = runGet(client)
+	default:
+		usage()
+		os.Exit(1)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "afxdpctl:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: afxdpctl log set <name=level|all=level>")
+	fmt.Fprintln(os.Stderr, "       afxdpctl log get")
+}
+
+func controlClient(socket string) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socket)
+			},
+		},
+	}
+}
+
+func runSet(client *http.Client, arg string) error {
+	name, level, ok := strings.Cut(arg, "=")
+	if !ok {
+		return fmt.Errorf("expected name=level, got %q", arg)
+	}
+
+	url := fmt.Sprintf("http://unix/levels/%s", name)
+	if name == "all" {
+		url = fmt.Sprintf("http://unix/levels?all=%s", level)
+	}
+
+	req, err := http.NewRequest(http.MethodPut, url, strings.NewReader(level))
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s", strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+func runGet(client *http.Client) error {
+	resp, err := client.Get("http://unix/levels")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(body))
+	return nil
+}
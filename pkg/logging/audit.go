@@ -0,0 +1,197 @@
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// AuditRecord is a single tamper-evident audit log line describing a
+// privileged operation: loading an XDP program, the UDS handshake
+// with a pod, pinning a BPF map, changing netdev ownership, and
+// similar security-sensitive actions. Records are written independent
+// of the current debug log level, so operators can prove which pod
+// requested which device and when without grepping mixed-severity
+// output.
+type AuditRecord struct {
+	Seq      uint64                 `json:"seq"`
+	Time     time.Time              `json:"time"`
+	Action   string                 `json:"action"`
+	Subject  string                 `json:"subject"`
+	Fields   map[string]interface{} `json:"fields,omitempty"`
+	PrevHash string                 `json:"prev_hash,omitempty"`
+	HMAC     string                 `json:"hmac,omitempty"`
+}
+
+// auditSink is the destination Auditf writes to. It is entirely
+// separate from the stderr/file sinks Printf/Entry/Logger write to.
+type auditSink struct {
+	mu       sync.Mutex
+	w        io.Writer
+	closer   io.Closer
+	seq      uint64
+	prevHash string
+	key      []byte // HMAC key; empty means records are hash-chained but not signed
+}
+
+// audit holds the single active audit sink, or nil until SetAuditFile
+// has been called, in which case Auditf is a no-op. It's an
+// atomic.Pointer rather than a bare *auditSink because SetAuditFile
+// can swap it concurrently with Auditf calls reading it.
+var audit atomic.Pointer[auditSink]
+
+// SetAuditFile points the audit stream at filename, creating it if
+// necessary and appending to it otherwise. If filename already holds
+// records from a prior process lifetime, the new sink resumes the hash
+// chain from the last one (seq and prevHash) instead of silently
+// restarting it at seq 0, so the chain still proves continuity across
+// a restart; if the last record can't be parsed, SetAuditFile returns
+// an error rather than masking the discontinuity. If key is non-empty,
+// each record is HMAC-signed over the previous record's hash, so any
+// reordering, deletion, or edit of the file breaks the chain from that
+// point on; with no key, records are still sequenced and hash-chained,
+// just not cryptographically signed.
+func SetAuditFile(filename string, key []byte) error {
+	seq, prevHash, err := resumeAuditChain(filename)
+	if err != nil {
+		return fmt.Errorf("logging: cannot resume audit chain from %s: %w", filename, err)
+	}
+
+	fp, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("logging: cannot open audit file %s: %w", filename, err)
+	}
+
+	newSink := &auditSink{w: fp, closer: fp, seq: seq, prevHash: prevHash, key: key}
+	if old := audit.Swap(newSink); old != nil {
+		_ = old.closer.Close()
+	}
+	return nil
+}
+
+// resumeAuditChain reads filename's last record, if any, and returns
+// the seq and prevHash a new auditSink should continue from. A
+// missing file resumes from the start of a fresh chain (seq 0, no
+// prevHash). It does not re-verify the chain all the way back to the
+// first record, only that the last line parses, so tampering earlier
+// than the last record isn't caught here - only breaks in continuity
+// from this point forward are.
+func resumeAuditChain(filename string) (seq uint64, prevHash string, err error) {
+	fp, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return 0, "", nil
+	}
+	if err != nil {
+		return 0, "", err
+	}
+	defer fp.Close()
+
+	var last []byte
+	scanner := bufio.NewScanner(fp)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if line := scanner.Bytes(); len(line) > 0 {
+			last = append(last[:0], line...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, "", err
+	}
+	if len(last) == 0 {
+		return 0, "", nil
+	}
+
+	var rec AuditRecord
+	if err := json.Unmarshal(last, &rec); err != nil {
+		return 0, "", fmt.Errorf("cannot parse last record: %w", err)
+	}
+
+	if rec.HMAC != "" {
+		return rec.Seq + 1, rec.HMAC, nil
+	}
+	sum := sha256.Sum256(append(last, '\n'))
+	return rec.Seq + 1, hex.EncodeToString(sum[:]), nil
+}
+
+// CloseAudit closes the current audit sink, if one is set.
+func CloseAudit() error {
+	a := audit.Load()
+	if a == nil {
+		return nil
+	}
+	return a.closer.Close()
+}
+
+// Auditf records a privileged action against subject (a pod, netdev,
+// pool, or container ID) with structured fields, writing a
+// tamper-evident JSON line to the audit stream. It is a no-op until
+// SetAuditFile has been called.
+func Auditf(action, subject string, fields map[string]interface{}) {
+	a := audit.Load()
+	if a == nil {
+		return
+	}
+	a.write(action, subject, fields)
+}
+
+func (a *auditSink) write(action, subject string, fields map[string]interface{}) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	rec := AuditRecord{
+		Seq:      a.seq,
+		Time:     time.Now(),
+		Action:   action,
+		Subject:  subject,
+		Fields:   fields,
+		PrevHash: a.prevHash,
+	}
+
+	if len(a.key) > 0 {
+		mac := hmac.New(sha256.New, a.key)
+		fmt.Fprintf(mac, "%d|%s|%s|%s|%v|%s", rec.Seq, rec.Time.Format(time.RFC3339Nano), rec.Action, rec.Subject, rec.Fields, rec.PrevHash)
+		rec.HMAC = hex.EncodeToString(mac.Sum(nil))
+	}
+
+	b, err := json.Marshal(rec)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s logging: cannot marshal audit record: %s\n", pluginName, err)
+		return
+	}
+	b = append(b, '\n')
+
+	if _, err := a.w.Write(b); err != nil {
+		fmt.Fprintf(os.Stderr, "%s logging: audit write failed: %s\n", pluginName, err)
+		return
+	}
+
+	a.seq++
+	if rec.HMAC != "" {
+		a.prevHash = rec.HMAC
+	} else {
+		sum := sha256.Sum256(b)
+		a.prevHash = hex.EncodeToString(sum[:])
+	}
+}
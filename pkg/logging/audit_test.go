@@ -0,0 +1,230 @@
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func readAuditRecords(t *testing.T, path string) []AuditRecord {
+	t.Helper()
+
+	fp, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("cannot open audit file: %s", err)
+	}
+	defer fp.Close()
+
+	var recs []AuditRecord
+	scanner := bufio.NewScanner(fp)
+	for scanner.Scan() {
+		var rec AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("cannot parse audit record %q: %s", scanner.Text(), err)
+		}
+		recs = append(recs, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("scanner error: %s", err)
+	}
+	return recs
+}
+
+func TestAuditfNoOpWithoutSetAuditFile(t *testing.T) {
+	if old := audit.Swap(nil); old != nil {
+		defer func() { audit.Store(old) }()
+	}
+	Auditf("load-xdp", "pod-a", nil) // must not panic
+}
+
+func TestAuditfWritesSequencedHashChain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	if err := SetAuditFile(path, nil); err != nil {
+		t.Fatalf("SetAuditFile: %s", err)
+	}
+	defer CloseAudit()
+
+	Auditf("load-xdp", "pod-a", map[string]interface{}{"netdev": "eth0"})
+	Auditf("pin-map", "pod-a", nil)
+	Auditf("uds-handshake", "pod-b", nil)
+
+	recs := readAuditRecords(t, path)
+	if len(recs) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(recs))
+	}
+
+	for i, rec := range recs {
+		if rec.Seq != uint64(i) {
+			t.Errorf("record %d: Seq = %d, want %d", i, rec.Seq, i)
+		}
+		if rec.HMAC != "" {
+			t.Errorf("record %d: expected no HMAC without a key, got %q", i, rec.HMAC)
+		}
+	}
+	if recs[0].PrevHash != "" {
+		t.Errorf("first record: expected empty PrevHash, got %q", recs[0].PrevHash)
+	}
+	if recs[1].PrevHash == "" || recs[2].PrevHash == "" {
+		t.Errorf("expected later records to carry a non-empty PrevHash")
+	}
+	if recs[1].PrevHash == recs[2].PrevHash {
+		t.Errorf("expected each record's PrevHash to be distinct")
+	}
+}
+
+func TestAuditfSignsWithHMACWhenKeyed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	key := []byte("test-key")
+	if err := SetAuditFile(path, key); err != nil {
+		t.Fatalf("SetAuditFile: %s", err)
+	}
+	defer CloseAudit()
+
+	Auditf("load-xdp", "pod-a", nil)
+	Auditf("pin-map", "pod-a", nil)
+
+	recs := readAuditRecords(t, path)
+	if len(recs) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(recs))
+	}
+
+	for i, rec := range recs {
+		if rec.HMAC == "" {
+			t.Fatalf("record %d: expected a non-empty HMAC when a key is set", i)
+		}
+		mac := hmac.New(sha256.New, key)
+		var prevHash string
+		if i > 0 {
+			prevHash = recs[i-1].HMAC
+		}
+		if prevHash != rec.PrevHash {
+			t.Errorf("record %d: PrevHash = %q, want %q", i, rec.PrevHash, prevHash)
+		}
+		fmt.Fprintf(mac, "%d|%s|%s|%s|%v|%s", rec.Seq, rec.Time.Format(time.RFC3339Nano), rec.Action, rec.Subject, rec.Fields, rec.PrevHash)
+		want := hex.EncodeToString(mac.Sum(nil))
+		if rec.HMAC != want {
+			t.Errorf("record %d: HMAC does not verify against the chain", i)
+		}
+	}
+
+	// Tampering with a record must break HMAC verification.
+	tampered := recs[0]
+	tampered.Subject = "pod-tampered"
+	mac := hmac.New(sha256.New, key)
+	fmt.Fprintf(mac, "%d|%s|%s|%s|%v|%s", tampered.Seq, tampered.Time.Format(time.RFC3339Nano), tampered.Action, tampered.Subject, tampered.Fields, tampered.PrevHash)
+	if hex.EncodeToString(mac.Sum(nil)) == recs[0].HMAC {
+		t.Fatalf("expected tampering with Subject to invalidate the HMAC")
+	}
+}
+
+func TestSetAuditFileResumesChainAcrossRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	key := []byte("test-key")
+
+	if err := SetAuditFile(path, key); err != nil {
+		t.Fatalf("SetAuditFile (1st session): %s", err)
+	}
+	Auditf("load-xdp", "pod-a", nil)
+	Auditf("pin-map", "pod-a", nil)
+	if err := CloseAudit(); err != nil {
+		t.Fatalf("CloseAudit: %s", err)
+	}
+
+	// Simulate a restart: SetAuditFile is called again against the
+	// same, already-populated file.
+	if err := SetAuditFile(path, key); err != nil {
+		t.Fatalf("SetAuditFile (2nd session): %s", err)
+	}
+	defer CloseAudit()
+	Auditf("unpin-map", "pod-a", nil)
+
+	recs := readAuditRecords(t, path)
+	if len(recs) != 3 {
+		t.Fatalf("expected 3 records across both sessions, got %d", len(recs))
+	}
+	if recs[2].Seq != 2 {
+		t.Fatalf("expected the post-restart record to continue the sequence at 2, got %d", recs[2].Seq)
+	}
+	if recs[2].PrevHash != recs[1].HMAC {
+		t.Fatalf("expected the post-restart record to chain from the pre-restart tail: got PrevHash=%q, want %q", recs[2].PrevHash, recs[1].HMAC)
+	}
+}
+
+func TestSetAuditFileStartsFreshWhenFileAbsent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "does-not-exist", "audit.log")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("MkdirAll: %s", err)
+	}
+	seq, prevHash, err := resumeAuditChain(path)
+	if err != nil {
+		t.Fatalf("resumeAuditChain: %s", err)
+	}
+	if seq != 0 || prevHash != "" {
+		t.Fatalf("expected a fresh chain (seq=0, prevHash=\"\"), got seq=%d prevHash=%q", seq, prevHash)
+	}
+
+	if err := SetAuditFile(path, nil); err != nil {
+		t.Fatalf("SetAuditFile: %s", err)
+	}
+	defer CloseAudit()
+}
+
+func TestSetAuditFileErrorsOnUnparsableTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	if err := os.WriteFile(path, []byte("not valid json\n"), 0600); err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+
+	if err := SetAuditFile(path, nil); err == nil {
+		t.Fatalf("expected SetAuditFile to error on an unparsable existing tail")
+	}
+}
+
+func TestResumeAuditChainWithoutKeyHashesLastLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	if err := SetAuditFile(path, nil); err != nil {
+		t.Fatalf("SetAuditFile: %s", err)
+	}
+	Auditf("load-xdp", "pod-a", nil)
+	if err := CloseAudit(); err != nil {
+		t.Fatalf("CloseAudit: %s", err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %s", err)
+	}
+	line := bytes.TrimRight(b, "\n")
+	sum := sha256.Sum256(append(line, '\n'))
+	want := hex.EncodeToString(sum[:])
+
+	_, prevHash, err := resumeAuditChain(path)
+	if err != nil {
+		t.Fatalf("resumeAuditChain: %s", err)
+	}
+	if prevHash != want {
+		t.Fatalf("prevHash = %q, want %q", prevHash, want)
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import "runtime"
+
+// callerPackage returns the fully qualified name (import path, package
+// and function, e.g. "example.com/pkg/cni.Add") of the function skip
+// frames up the stack; loggerFor reduces it to the unqualified package
+// name. It uses the same skip convention as
+// runtime.Caller (skip=0 identifies the caller of callerPackage
+// itself). Unlike runtime.Caller, which always heap-allocates a
+// one-element slice internally, callerPackage resolves the frame with
+// a stack-local array, so it doesn't allocate. That matters here:
+// loggerFor calls this on every Printf/Entry/Logger call site to
+// figure out which subsystem, if any, should gate the call.
+func callerPackage(skip int) (name string, ok bool) {
+	var pcs [1]uintptr
+	if runtime.Callers(skip+2, pcs[:]) == 0 {
+		return "", false
+	}
+
+	fn := runtime.FuncForPC(pcs[0] - 1)
+	if fn == nil {
+		return "", false
+	}
+	return fn.Name(), true
+}
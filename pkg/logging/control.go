@@ -0,0 +1,143 @@
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// DefaultControlSocket is the Unix-domain socket the device plugin
+// exposes its log level control API on by default.
+const DefaultControlSocket = "/var/run/afxdp/log.sock"
+
+// ControlServer exposes a small Unix-domain HTTP API for inspecting
+// and changing per-subsystem log levels at runtime, so operators don't
+// have to restart a pod to raise verbosity in one component. See
+// StartControlSocket.
+type ControlServer struct {
+	listener net.Listener
+	server   *http.Server
+}
+
+// StartControlSocket starts serving the log level control API on a
+// Unix-domain socket at path. Any existing socket file at path is
+// removed first. The server runs in the background until Close is
+// called.
+//
+// Supported requests:
+//
+//	GET  /levels            list every registered subsystem's level
+//	PUT  /levels/{name}      set subsystem name's level (body: level)
+//	PUT  /levels?all=debug   set every subsystem (and the default)
+func StartControlSocket(path string) (*ControlServer, error) {
+	_ = os.Remove(path)
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("logging: cannot listen on %s: %w", path, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/levels", handleLevels)
+	mux.HandleFunc("/levels/", handleLevel)
+
+	cs := &ControlServer{
+		listener: listener,
+		server:   &http.Server{Handler: mux},
+	}
+
+	go func() {
+		if err := cs.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			fmt.Fprintf(os.Stderr, "%s logging: control socket stopped: %s\n", pluginName, err)
+		}
+	}()
+
+	return cs, nil
+}
+
+// Close stops the control server and removes its socket file.
+func (cs *ControlServer) Close() error {
+	err := cs.server.Close()
+	_ = os.Remove(cs.listener.Addr().String())
+	return err
+}
+
+func handleLevels(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		levels := Levels()
+		out := make(map[string]string, len(levels))
+		for name, level := range levels {
+			out[name] = level.String()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(out)
+
+	case http.MethodPut:
+		all := r.URL.Query().Get("all")
+		if all == "" {
+			http.Error(w, "missing ?all=<level>", http.StatusBadRequest)
+			return
+		}
+		level := GetLoggingLevel(all)
+		if level == UnknownLevel {
+			http.Error(w, fmt.Sprintf("unknown level %q", all), http.StatusBadRequest)
+			return
+		}
+		SetAllLevels(level)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func handleLevel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, "/levels/")
+	if name == "" {
+		http.Error(w, "missing subsystem name", http.StatusBadRequest)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "cannot read request body", http.StatusBadRequest)
+		return
+	}
+
+	level := GetLoggingLevel(strings.TrimSpace(string(body)))
+	if level == UnknownLevel {
+		http.Error(w, fmt.Sprintf("unknown level %q", body), http.StatusBadRequest)
+		return
+	}
+
+	if err := SetLevelByName(name, level); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandleLevelsGet(t *testing.T) {
+	l := registerTestLogger(t, "test-control-a")
+	l.SetLevel(DebugLevel)
+
+	req := httptest.NewRequest(http.MethodGet, "/levels", nil)
+	rec := httptest.NewRecorder()
+	handleLevels(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+
+	var out map[string]string
+	if err := json.NewDecoder(rec.Body).Decode(&out); err != nil {
+		t.Fatalf("cannot decode response: %s", err)
+	}
+	if out["test-control-a"] != "debug" {
+		t.Fatalf("levels[%q] = %q, want debug", l.Name(), out["test-control-a"])
+	}
+}
+
+func TestHandleLevelsPutAll(t *testing.T) {
+	l := registerTestLogger(t, "test-control-b")
+	defer SetLogLevel("warning")
+
+	req := httptest.NewRequest(http.MethodPut, "/levels?all=error", nil)
+	rec := httptest.NewRecorder()
+	handleLevels(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", rec.Code)
+	}
+	if l.Level() != ErrorLevel {
+		t.Fatalf("Level() = %v, want error", l.Level())
+	}
+}
+
+func TestHandleLevelsPutMissingQuery(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPut, "/levels", nil)
+	rec := httptest.NewRecorder()
+	handleLevels(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", rec.Code)
+	}
+}
+
+func TestHandleLevelsMethodNotAllowed(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/levels", nil)
+	rec := httptest.NewRecorder()
+	handleLevels(rec, req)
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("status = %d, want 405", rec.Code)
+	}
+}
+
+func TestHandleLevelPut(t *testing.T) {
+	l := registerTestLogger(t, "test-control-c")
+
+	req := httptest.NewRequest(http.MethodPut, "/levels/test-control-c", strings.NewReader("debug"))
+	rec := httptest.NewRecorder()
+	handleLevel(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", rec.Code)
+	}
+	if l.Level() != DebugLevel {
+		t.Fatalf("Level() = %v, want debug", l.Level())
+	}
+}
+
+func TestHandleLevelUnknownSubsystem(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPut, "/levels/no-such-subsystem", strings.NewReader("debug"))
+	rec := httptest.NewRecorder()
+	handleLevel(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", rec.Code)
+	}
+}
+
+func TestHandleLevelUnknownLevel(t *testing.T) {
+	registerTestLogger(t, "test-control-d")
+
+	req := httptest.NewRequest(http.MethodPut, "/levels/test-control-d", strings.NewReader("bogus"))
+	rec := httptest.NewRecorder()
+	handleLevel(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", rec.Code)
+	}
+}
+
+func TestHandleLevelMethodNotAllowed(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/levels/test-control-e", nil)
+	rec := httptest.NewRecorder()
+	handleLevel(rec, req)
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("status = %d, want 405", rec.Code)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// entryCallDepth mirrors callDepth: Entry.log plays the same role for
+// chained entries that Printf plays for the package-level helpers, so
+// the same number of frames separate it from the original caller.
+const entryCallDepth = 2
+
+// Entry carries the structured context for a single log line. Entries
+// are created with WithField/WithFields and are chainable, so callers
+// can attach identifiers (pod, netdev, pool, container ID) that flow
+// through to the final formatted output.
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	File    string
+	Line    int
+	Fields  map[string]interface{}
+}
+
+func newEntry() *Entry {
+	return &Entry{Fields: make(map[string]interface{})}
+}
+
+// WithField starts a new Entry carrying key=value.
+func WithField(key string, value interface{}) *Entry {
+	return newEntry().WithField(key, value)
+}
+
+// WithFields starts a new Entry carrying every key/value pair in fields.
+func WithFields(fields map[string]interface{}) *Entry {
+	return newEntry().WithFields(fields)
+}
+
+// WithField attaches key=value to e and returns e for chaining.
+func (e *Entry) WithField(key string, value interface{}) *Entry {
+	e.Fields[key] = value
+	return e
+}
+
+// WithFields attaches every key/value pair in fields to e and returns
+// e for chaining.
+func (e *Entry) WithFields(fields map[string]interface{}) *Entry {
+	for key, value := range fields {
+		e.Fields[key] = value
+	}
+	return e
+}
+
+// log finalizes e at level and writes it to the configured sinks. If
+// the calling package has registered its own Logger (see
+// RegisterLogger), that Logger's level gates the call instead of the
+// global level.
+func (e *Entry) log(level Level, format string, a ...interface{}) {
+	effectiveLevel := loggingLevel
+	if l := loggerFor(entryCallDepth); l != nil {
+		effectiveLevel = l.Level()
+	}
+	if level > effectiveLevel {
+		return
+	}
+
+	e.Time = time.Now()
+	e.Level = level
+	e.Message = fmt.Sprintf(format, a...)
+
+	if effectiveLevel == DebugLevel {
+		_, path, line, ok := runtime.Caller(entryCallDepth)
+		if ok {
+			e.File = filepath.Base(path)
+			e.Line = line
+		}
+	}
+
+	write(e)
+}
+
+// Debugf finalizes e at debug level.
+func (e *Entry) Debugf(format string, a ...interface{}) {
+	e.log(DebugLevel, format, a...)
+}
+
+// Infof finalizes e at info level.
+func (e *Entry) Infof(format string, a ...interface{}) {
+	e.log(InfoLevel, format, a...)
+}
+
+// Warningf finalizes e at warning level.
+func (e *Entry) Warningf(format string, a ...interface{}) {
+	e.log(WarningLevel, format, a...)
+}
+
+// Errorf finalizes e at error level and returns the formatted message
+// as an error, mirroring the package-level Errorf.
+func (e *Entry) Errorf(format string, a ...interface{}) error {
+	e.log(ErrorLevel, format, a...)
+	return fmt.Errorf(format, a...)
+}
+
+// Panicf finalizes e at panic level plus a stack trace, mirroring the
+// package-level Panicf. This should be used only for unrecoverable
+// errors.
+func (e *Entry) Panicf(format string, a ...interface{}) {
+	e.log(PanicLevel, format, a...)
+	e.log(PanicLevel, "========= Stack trace output ========")
+	e.log(PanicLevel, "%+v", errors.New("CNDP K8s Plugin Panic"))
+	e.log(PanicLevel, "========= Stack trace output end ========")
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithFieldChaining(t *testing.T) {
+	e := WithField("pod", "pod-a").WithField("netdev", "eth0")
+	if e.Fields["pod"] != "pod-a" || e.Fields["netdev"] != "eth0" {
+		t.Fatalf("expected both fields to be set, got %v", e.Fields)
+	}
+}
+
+func TestWithFieldsMerge(t *testing.T) {
+	e := WithFields(map[string]interface{}{"pod": "pod-a", "netdev": "eth0"})
+	e.WithFields(map[string]interface{}{"netdev": "eth1", "pool": "pool-a"})
+
+	if e.Fields["pod"] != "pod-a" {
+		t.Fatalf("expected pod to survive the merge, got %v", e.Fields["pod"])
+	}
+	if e.Fields["netdev"] != "eth1" {
+		t.Fatalf("expected netdev to be overwritten by the later WithFields, got %v", e.Fields["netdev"])
+	}
+	if e.Fields["pool"] != "pool-a" {
+		t.Fatalf("expected pool to be added, got %v", e.Fields["pool"])
+	}
+}
+
+func TestEntryRespectsLevel(t *testing.T) {
+	read := captureLogOutput(t)
+	SetLogLevel("warning")
+
+	WithField("pod", "pod-a").Infof("should be filtered")
+	if strings.Contains(read(), "should be filtered") {
+		t.Fatalf("expected info to be filtered at warning level, got %q", read())
+	}
+
+	WithField("pod", "pod-a").Warningf("visible %s", "message")
+	if !strings.Contains(read(), "visible message") {
+		t.Fatalf("expected warning line to be written, got %q", read())
+	}
+}
+
+func TestEntryErrorfReturnsFormattedError(t *testing.T) {
+	SetLogStderr(false)
+	e := newEntry()
+	err := e.Errorf("netdev %s not found", "eth0")
+	if err == nil || err.Error() != "netdev eth0 not found" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestEntryCapturesCallerLocationAtDebugLevel(t *testing.T) {
+	read := captureLogOutput(t)
+	SetLogLevel("debug")
+	SetFormatter(&TextFormatter{})
+
+	WithField("pod", "pod-a").Debugf("at debug level") // logged from this file
+	if !strings.Contains(read(), "entry_test.go:") {
+		t.Fatalf("expected caller file:line in debug output, got %q", read())
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Formatter renders a log Entry into the bytes that get written to the
+// configured sinks. Implementations must be safe to call repeatedly
+// and must not retain entry after Format returns.
+type Formatter interface {
+	Format(entry *Entry) ([]byte, error)
+}
+
+// TextFormatter renders entries as "timestamp [level] file:line message
+// key=value ...", the plain form the plugin has always logged in.
+type TextFormatter struct{}
+
+// Format implements Formatter.
+func (f *TextFormatter) Format(entry *Entry) ([]byte, error) {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "%s [%s] ", entry.Time.Format(defaultTimestampFormat), entry.Level)
+	if entry.File != "" {
+		fmt.Fprintf(&buf, "%s:%d ", entry.File, entry.Line)
+	}
+	buf.WriteString(entry.Message)
+
+	for _, key := range sortedFieldKeys(entry.Fields) {
+		fmt.Fprintf(&buf, " %s=%v", key, entry.Fields[key])
+	}
+	buf.WriteByte('\n')
+
+	return buf.Bytes(), nil
+}
+
+// JSONFormatter renders entries as one JSON object per line, with
+// fields for timestamp, level, plugin, message, caller file/line, and
+// any structured fields attached via WithField/WithFields. Intended to
+// be shipped straight into fluentd/loki pipelines without regex
+// parsing.
+type JSONFormatter struct{}
+
+// Format implements Formatter.
+func (f *JSONFormatter) Format(entry *Entry) ([]byte, error) {
+	line := make(map[string]interface{}, len(entry.Fields)+5)
+	for key, value := range entry.Fields {
+		line[key] = value
+	}
+
+	line["timestamp"] = entry.Time.Format(time.RFC3339Nano)
+	line["level"] = entry.Level.String()
+	line["plugin"] = pluginName
+	line["message"] = entry.Message
+	if entry.File != "" {
+		line["file"] = entry.File
+		line["line"] = entry.Line
+	}
+
+	b, err := json.Marshal(line)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, '\n'), nil
+}
+
+// sortedFieldKeys returns the keys of fields in a stable order, so
+// that TextFormatter output is deterministic.
+func sortedFieldKeys(fields map[string]interface{}) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
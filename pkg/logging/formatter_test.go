@@ -0,0 +1,138 @@
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testEntry() *Entry {
+	e := newEntry()
+	e.Time = time.Date(2021, time.January, 2, 3, 4, 5, 0, time.UTC)
+	e.Level = InfoLevel
+	e.Message = "hello world"
+	e.Fields["pod"] = "pod-a"
+	e.Fields["netdev"] = "eth0"
+	return e
+}
+
+func TestTextFormatterFormat(t *testing.T) {
+	f := &TextFormatter{}
+	b, err := f.Format(testEntry())
+	if err != nil {
+		t.Fatalf("Format returned error: %s", err)
+	}
+	out := string(b)
+
+	if !strings.HasPrefix(out, "2021-01-02T03:04:05Z [info] hello world") {
+		t.Fatalf("unexpected output: %q", out)
+	}
+	if !strings.Contains(out, "netdev=eth0") || !strings.Contains(out, "pod=pod-a") {
+		t.Fatalf("expected sorted fields in output: %q", out)
+	}
+	if strings.Index(out, "netdev=eth0") > strings.Index(out, "pod=pod-a") {
+		t.Fatalf("expected fields sorted alphabetically: %q", out)
+	}
+	if !strings.HasSuffix(out, "\n") {
+		t.Fatalf("expected trailing newline: %q", out)
+	}
+}
+
+func TestTextFormatterOmitsFileWhenUnset(t *testing.T) {
+	f := &TextFormatter{}
+	b, err := f.Format(testEntry())
+	if err != nil {
+		t.Fatalf("Format returned error: %s", err)
+	}
+	if strings.Contains(string(b), ".go:") {
+		t.Fatalf("did not expect file:line when File is unset: %q", b)
+	}
+
+	e := testEntry()
+	e.File = "foo.go"
+	e.Line = 42
+	b, err = f.Format(e)
+	if err != nil {
+		t.Fatalf("Format returned error: %s", err)
+	}
+	if !strings.Contains(string(b), "foo.go:42") {
+		t.Fatalf("expected file:line in output: %q", b)
+	}
+}
+
+func TestJSONFormatterFormat(t *testing.T) {
+	SetPluginName("testplugin")
+	defer SetPluginName("unnamed plugin")
+
+	f := &JSONFormatter{}
+	b, err := f.Format(testEntry())
+	if err != nil {
+		t.Fatalf("Format returned error: %s", err)
+	}
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(b, &line); err != nil {
+		t.Fatalf("output is not valid JSON: %s (%q)", err, b)
+	}
+
+	want := map[string]string{
+		"level":   "info",
+		"plugin":  "testplugin",
+		"message": "hello world",
+		"pod":     "pod-a",
+		"netdev":  "eth0",
+	}
+	for key, value := range want {
+		if got, _ := line[key].(string); got != value {
+			t.Errorf("field %q = %q, want %q", key, got, value)
+		}
+	}
+	if _, ok := line["file"]; ok {
+		t.Errorf("did not expect a file field when File is unset: %v", line)
+	}
+}
+
+func TestJSONFormatterIncludesFileLine(t *testing.T) {
+	e := testEntry()
+	e.File = "foo.go"
+	e.Line = 42
+
+	f := &JSONFormatter{}
+	b, err := f.Format(e)
+	if err != nil {
+		t.Fatalf("Format returned error: %s", err)
+	}
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(b, &line); err != nil {
+		t.Fatalf("output is not valid JSON: %s", err)
+	}
+	if line["file"] != "foo.go" {
+		t.Errorf("file = %v, want foo.go", line["file"])
+	}
+	if line["line"] != float64(42) {
+		t.Errorf("line = %v, want 42", line["line"])
+	}
+}
+
+func TestSortedFieldKeys(t *testing.T) {
+	keys := sortedFieldKeys(map[string]interface{}{"c": 1, "a": 2, "b": 3})
+	if strings.Join(keys, ",") != "a,b,c" {
+		t.Fatalf("expected sorted keys, got %v", keys)
+	}
+}
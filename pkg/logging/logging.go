@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
@@ -48,10 +49,15 @@ const (
 )
 
 var loggingStderr bool
-var loggingFp *os.File
 var loggingLevel Level
 var pluginName string
 
+// activeFormatter is an atomic.Pointer rather than a bare Formatter
+// because SetFormatter/SetFormatterName can swap it concurrently with
+// Printf/Entry/Logger calls reading it on every write - the same
+// pattern used for sink and audit.
+var activeFormatter atomic.Pointer[Formatter]
+
 //callDepth sets the number of function calls to retrieve the stack trace for filepath.
 const callDepth = 2
 const defaultTimestampFormat = time.RFC3339
@@ -72,33 +78,64 @@ func (l Level) String() string {
 	return "unknown"
 }
 
-// Printf prints logging to logfile
+// Printf prints logging to logfile. If the calling package has
+// registered its own Logger (see RegisterLogger), that Logger's level
+// gates the call instead of the global level.
 func Printf(level Level, format string, a ...interface{}) {
-	header := "%s [%s] "
-	t := time.Now()
-	if level > loggingLevel {
+	effectiveLevel := loggingLevel
+	if l := loggerFor(callDepth); l != nil {
+		effectiveLevel = l.Level()
+	}
+	if level > effectiveLevel {
 		return
 	}
 
-	if loggingLevel == DebugLevel {
+	entry := newEntry()
+	entry.Time = time.Now()
+	entry.Level = level
+	entry.Message = fmt.Sprintf(format, a...)
+
+	if effectiveLevel == DebugLevel {
 		_, path, line, ok := runtime.Caller(callDepth)
 		if ok {
-			file := filepath.Base(path)
-			format = fmt.Sprintf("%s:%d %s", file, line, format)
+			entry.File = filepath.Base(path)
+			entry.Line = line
 		}
 	}
 
+	write(entry)
+}
+
+// write renders entry with the active Formatter and writes the result
+// to whichever sinks (stderr, log file) are currently enabled.
+func write(entry *Entry) {
+	b, err := (*activeFormatter.Load()).Format(entry)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s logging: failed to format log entry: %s\n", pluginName, err)
+		return
+	}
+
 	if loggingStderr {
-		fmt.Fprintf(os.Stderr, header, t.Format(defaultTimestampFormat), level)
-		fmt.Fprintf(os.Stderr, format, a...)
-		fmt.Fprintf(os.Stderr, "\n")
+		os.Stderr.Write(b)
+	}
+
+	if s := sink.Load(); s != nil {
+		s.Write(b)
 	}
+}
 
-	if loggingFp != nil {
-		fmt.Fprintf(loggingFp, header, t.Format(defaultTimestampFormat), level)
-		fmt.Fprintf(loggingFp, format, a...)
-		fmt.Fprintf(loggingFp, "\n")
+// Enabled reports whether level would currently be logged by Printf
+// from the caller's package (honoring any per-subsystem Logger
+// registered for it). Most callers don't need this - Printf's own
+// filtered path is already allocation-free - but it's useful to guard
+// a whole block of expensive argument preparation (not just a single
+// Debugf call) before doing any of that work.
+func Enabled(level Level) bool {
+	effectiveLevel := loggingLevel
+	if l := loggerFor(1); l != nil {
+		effectiveLevel = l.Level()
 	}
+	return level <= effectiveLevel
 }
 
 // Debugf prints logging if logging level >= debug
@@ -161,18 +198,23 @@ func SetLogStderr(enable bool) {
 	loggingStderr = enable
 }
 
-// SetLogFile sets logging file
+// SetLogFile sets logging file, replacing any previously configured
+// one. Writes to it are safe under concurrent Printf calls from CNI
+// invocations and device plugin gRPC handlers; see fileSink.
 func SetLogFile(filename string) {
 	if filename == "" {
 		return
 	}
 
-	fp, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	s, err := newFileSink(filename)
 	if err != nil {
-		loggingFp = nil
-		fmt.Fprintf(os.Stderr, "%s logging: cannot open %s", pluginName, filename)
+		fmt.Fprintf(os.Stderr, "%s logging: cannot open %s\n", pluginName, filename)
+		return
+	}
+
+	if old := sink.Swap(s); old != nil {
+		_ = old.Close()
 	}
-	loggingFp = fp
 }
 
 //SetPluginName sets plugin name
@@ -182,9 +224,32 @@ func SetPluginName(PluginStr string) {
 	}
 }
 
+// SetFormatter sets the Formatter used to render log entries before
+// they are written to the configured sinks.
+func SetFormatter(f Formatter) {
+	if f == nil {
+		return
+	}
+	activeFormatter.Store(&f)
+}
+
+// SetFormatterName selects the Formatter by name ("text" or "json"),
+// so it can be wired straight from configuration. Unknown names leave
+// the current formatter unchanged.
+func SetFormatterName(name string) {
+	switch strings.ToLower(name) {
+	case "json":
+		SetFormatter(&JSONFormatter{})
+	case "text":
+		SetFormatter(&TextFormatter{})
+	default:
+		fmt.Fprintf(os.Stderr, "%s logging: cannot set log format to %s\n", pluginName, name)
+	}
+}
+
 func init() {
 	loggingStderr = true
-	loggingFp = nil
 	loggingLevel = WarningLevel
 	pluginName = "unnamed plugin"
+	SetFormatter(&TextFormatter{})
 }
\ No newline at end of file
@@ -0,0 +1,79 @@
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import "testing"
+
+// BenchmarkDebugfFiltered exercises Debugf when debug logging is
+// disabled, the common case for per-packet/per-syscall probes left
+// compiled into the AF_XDP fast path. It should report zero
+// allocations.
+func BenchmarkDebugfFiltered(b *testing.B) {
+	SetLogLevel("warning")
+	SetLogStderr(false)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Debugf("packet %d dropped: %s", i, "checksum mismatch")
+	}
+}
+
+// BenchmarkEntryFilteredWithField exercises the WithField/Debugf chain
+// when debug logging is disabled. It allocates even more than plain
+// Debugf: WithField must build a chainable *Entry (and its Fields map)
+// before any level check can run.
+func BenchmarkEntryFilteredWithField(b *testing.B) {
+	SetLogLevel("warning")
+	SetLogStderr(false)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		WithField("netdev", "eth0").Debugf("packet %d dropped", i)
+	}
+}
+
+// BenchmarkEnabledGuardFiltered exercises the Enabled guard pattern,
+// useful when a caller wants to skip a whole block of expensive
+// argument preparation - not just one Debugf call - when the level is
+// disabled. It should also report zero allocations.
+func BenchmarkEnabledGuardFiltered(b *testing.B) {
+	SetLogLevel("warning")
+	SetLogStderr(false)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if Enabled(DebugLevel) {
+			Debugf("packet %d dropped: %s", i, "checksum mismatch")
+		}
+	}
+}
+
+// BenchmarkDebugfFilteredRegistered exercises the filtered path in the
+// shape it actually runs in once any package has called RegisterLogger
+// (exactly what chunk0-2 tells integrators to do for "cni",
+// "deviceplugin", "bpf", ...): loggerFor's registryEmpty short-circuit
+// no longer applies, and every Printf/Entry/Logger call pays for a real
+// caller-package lookup via loggerFor. It must still report zero
+// allocations - see callerPackage in caller.go.
+func BenchmarkDebugfFilteredRegistered(b *testing.B) {
+	RegisterLogger("benchmark-other-subsystem")
+	SetLogLevel("warning")
+	SetLogStderr(false)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Debugf("packet %d dropped: %s", i, "checksum mismatch")
+	}
+}
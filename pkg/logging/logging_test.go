@@ -0,0 +1,142 @@
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// captureLogOutput points the package's log file sink at a temp file
+// for the duration of the test, disables stderr output, and returns a
+// function that reads back whatever has been written so far. State is
+// restored when the test ends.
+func captureLogOutput(t *testing.T) func() string {
+	t.Helper()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.log")
+
+	prevStderr := loggingStderr
+	prevLevel := loggingLevel
+	SetLogStderr(false)
+	SetLogFile(path)
+
+	t.Cleanup(func() {
+		if s := sink.Swap(nil); s != nil {
+			_ = s.Close()
+		}
+		loggingStderr = prevStderr
+		loggingLevel = prevLevel
+	})
+
+	return func() string {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("cannot read captured log output: %s", err)
+		}
+		return string(b)
+	}
+}
+
+func TestGetLoggingLevel(t *testing.T) {
+	cases := map[string]Level{
+		"debug":   DebugLevel,
+		"Info":    InfoLevel,
+		"WARNING": WarningLevel,
+		"error":   ErrorLevel,
+		"panic":   PanicLevel,
+		"bogus":   UnknownLevel,
+	}
+	for in, want := range cases {
+		if got := GetLoggingLevel(in); got != want {
+			t.Errorf("GetLoggingLevel(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestPrintfFiltersByLevel(t *testing.T) {
+	read := captureLogOutput(t)
+	SetLogLevel("warning")
+
+	Debugf("should be filtered")
+	Infof("should also be filtered")
+	Warningf("visible warning")
+	Errorf("visible error")
+
+	out := read()
+	if strings.Contains(out, "should be filtered") || strings.Contains(out, "should also be filtered") {
+		t.Fatalf("expected debug/info to be filtered, got %q", out)
+	}
+	if !strings.Contains(out, "visible warning") || !strings.Contains(out, "visible error") {
+		t.Fatalf("expected warning/error lines, got %q", out)
+	}
+}
+
+func TestPrintfCapturesCallerLocationAtDebugLevel(t *testing.T) {
+	read := captureLogOutput(t)
+	SetLogLevel("debug")
+	SetFormatter(&TextFormatter{})
+
+	Debugf("at debug level") // logged from this file
+
+	out := read()
+	if !strings.Contains(out, "logging_test.go:") {
+		t.Fatalf("expected caller file:line in debug output, got %q", out)
+	}
+}
+
+func TestErrorfReturnsFormattedError(t *testing.T) {
+	SetLogStderr(false)
+	err := Errorf("netdev %s not found", "eth0")
+	if err == nil || err.Error() != "netdev eth0 not found" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSetFormatterName(t *testing.T) {
+	defer SetFormatter(&TextFormatter{})
+
+	SetFormatterName("json")
+	if _, ok := (*activeFormatter.Load()).(*JSONFormatter); !ok {
+		t.Fatalf("expected JSONFormatter after SetFormatterName(\"json\")")
+	}
+
+	SetFormatterName("text")
+	if _, ok := (*activeFormatter.Load()).(*TextFormatter); !ok {
+		t.Fatalf("expected TextFormatter after SetFormatterName(\"text\")")
+	}
+
+	SetFormatterName("bogus")
+	if _, ok := (*activeFormatter.Load()).(*TextFormatter); !ok {
+		t.Fatalf("expected unknown formatter name to leave the formatter unchanged")
+	}
+}
+
+func TestSetPluginName(t *testing.T) {
+	defer SetPluginName("unnamed plugin")
+
+	SetPluginName("myplugin")
+	if pluginName != "myplugin" {
+		t.Fatalf("pluginName = %q, want myplugin", pluginName)
+	}
+
+	SetPluginName("")
+	if pluginName != "myplugin" {
+		t.Fatalf("expected empty name to be ignored, got %q", pluginName)
+	}
+}
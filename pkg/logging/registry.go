@@ -0,0 +1,227 @@
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// loggerCallDepth mirrors callDepth: Logger.log plays the same role
+// for an explicit Logger handle that Printf plays for the
+// package-level helpers, so the same number of frames separate it
+// from the original caller.
+const loggerCallDepth = 2
+
+// Logger is a handle to a single subsystem's log level (e.g. "cni",
+// "deviceplugin", "bpf"). Raising or lowering a Logger's level only
+// affects log lines emitted through it, or routed to it by package
+// name, leaving every other subsystem untouched.
+type Logger struct {
+	name  string
+	level Level
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]*Logger{}
+
+	// registryEmpty lets loggerFor skip the caller-package lookup
+	// entirely until a subsystem has actually been registered. It's
+	// purely an extra short-circuit for the common unconfigured case -
+	// the lookup itself (see callerPackage in caller.go) never
+	// allocates, so it doesn't cost anything once other packages start
+	// calling RegisterLogger.
+	registryEmpty atomic.Bool
+)
+
+func init() {
+	registryEmpty.Store(true)
+}
+
+// RegisterLogger returns the Logger for name, creating it at the
+// current global level if it does not already exist. Safe to call
+// repeatedly, e.g. from a package's init - the same Logger is
+// returned every time for a given name.
+func RegisterLogger(name string) *Logger {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if l, ok := registry[name]; ok {
+		return l
+	}
+
+	l := &Logger{name: name, level: loggingLevel}
+	registry[name] = l
+	registryEmpty.Store(false)
+	return l
+}
+
+// Levels returns a snapshot of every registered subsystem's current
+// level, keyed by name.
+func Levels() map[string]Level {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	levels := make(map[string]Level, len(registry))
+	for name, l := range registry {
+		levels[name] = l.Level()
+	}
+	return levels
+}
+
+// SetLevelByName changes the level of the registered logger called
+// name. It returns an error if no such logger is registered.
+func SetLevelByName(name string, level Level) error {
+	registryMu.RLock()
+	l, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("logging: no such subsystem %q", name)
+	}
+	l.SetLevel(level)
+	return nil
+}
+
+// SetAllLevels changes the level of every registered subsystem, plus
+// the package-wide default that new Loggers and unregistered callers
+// fall back to.
+func SetAllLevels(level Level) {
+	registryMu.Lock()
+	// Assign l.level directly rather than calling l.SetLevel, which
+	// would try to re-acquire registryMu and deadlock.
+	for _, l := range registry {
+		l.level = level
+	}
+	registryMu.Unlock()
+	loggingLevel = level
+}
+
+// Name returns l's subsystem name.
+func (l *Logger) Name() string {
+	return l.name
+}
+
+// Level returns l's current level.
+func (l *Logger) Level() Level {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return l.level
+}
+
+// SetLevel changes l's level at runtime, e.g. from the control socket.
+func (l *Logger) SetLevel(level Level) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	l.level = level
+}
+
+// log finalizes a log line at level for subsystem l and writes it to
+// the configured sinks.
+func (l *Logger) log(level Level, format string, a ...interface{}) {
+	if level > l.Level() {
+		return
+	}
+
+	entry := newEntry()
+	entry.Time = time.Now()
+	entry.Level = level
+	entry.Message = fmt.Sprintf(format, a...)
+	entry.Fields["subsystem"] = l.name
+
+	if l.Level() == DebugLevel {
+		_, path, line, ok := runtime.Caller(loggerCallDepth)
+		if ok {
+			entry.File = filepath.Base(path)
+			entry.Line = line
+		}
+	}
+
+	write(entry)
+}
+
+// Debugf prints logging if l's level >= debug.
+func (l *Logger) Debugf(format string, a ...interface{}) {
+	l.log(DebugLevel, format, a...)
+}
+
+// Infof prints logging if l's level >= info.
+func (l *Logger) Infof(format string, a ...interface{}) {
+	l.log(InfoLevel, format, a...)
+}
+
+// Warningf prints logging if l's level >= warning.
+func (l *Logger) Warningf(format string, a ...interface{}) {
+	l.log(WarningLevel, format, a...)
+}
+
+// Errorf prints logging if l's level >= error.
+func (l *Logger) Errorf(format string, a ...interface{}) error {
+	l.log(ErrorLevel, format, a...)
+	return fmt.Errorf(format, a...)
+}
+
+// Panicf prints logging plus stack trace. This should be used only
+// for unrecoverable errors.
+func (l *Logger) Panicf(format string, a ...interface{}) {
+	l.log(PanicLevel, format, a...)
+	l.log(PanicLevel, "========= Stack trace output ========")
+	l.log(PanicLevel, "%+v", errors.New("CNDP K8s Plugin Panic"))
+	l.log(PanicLevel, "========= Stack trace output end ========")
+}
+
+// loggerFor resolves the per-subsystem Logger that should handle a
+// call made skip frames up the stack (using the same skip value that
+// would locate the original caller via runtime.Caller from that
+// point), or nil if the calling package has not registered one. This
+// lets package-level Debugf/Infof/etc. be routed to the right
+// Logger without the caller needing an explicit handle.
+//
+// The caller-package lookup (callerPackage, in caller.go) never
+// allocates, so loggerFor stays on Printf's zero-allocation filtered
+// path even once other packages have called RegisterLogger;
+// registryEmpty just skips the lookup entirely in the common case
+// where nothing has been registered at all.
+func loggerFor(skip int) *Logger {
+	if registryEmpty.Load() {
+		return nil
+	}
+
+	name, ok := callerPackage(skip + 1)
+	if !ok {
+		return nil
+	}
+
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[:i]
+	}
+	if name == "" {
+		return nil
+	}
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return registry[name]
+}
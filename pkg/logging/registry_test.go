@@ -0,0 +1,129 @@
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"strings"
+	"testing"
+)
+
+// registerTestLogger registers a uniquely-named Logger and removes it
+// from the registry when the test ends, so registry state doesn't leak
+// between tests.
+func registerTestLogger(t *testing.T, name string) *Logger {
+	t.Helper()
+	l := RegisterLogger(name)
+	t.Cleanup(func() {
+		registryMu.Lock()
+		delete(registry, name)
+		registryMu.Unlock()
+	})
+	return l
+}
+
+func TestRegisterLoggerReturnsSameInstance(t *testing.T) {
+	SetLogLevel("warning")
+	l1 := registerTestLogger(t, "test-subsystem-a")
+	l2 := RegisterLogger("test-subsystem-a")
+	if l1 != l2 {
+		t.Fatalf("expected RegisterLogger to return the same *Logger for the same name")
+	}
+	if l1.Name() != "test-subsystem-a" {
+		t.Fatalf("Name() = %q, want test-subsystem-a", l1.Name())
+	}
+	if l1.Level() != WarningLevel {
+		t.Fatalf("expected new Logger to start at the global level, got %v", l1.Level())
+	}
+}
+
+func TestLevels(t *testing.T) {
+	l := registerTestLogger(t, "test-subsystem-b")
+	l.SetLevel(DebugLevel)
+
+	levels := Levels()
+	if levels["test-subsystem-b"] != DebugLevel {
+		t.Fatalf("Levels()[%q] = %v, want debug", l.Name(), levels["test-subsystem-b"])
+	}
+}
+
+func TestSetLevelByName(t *testing.T) {
+	l := registerTestLogger(t, "test-subsystem-c")
+
+	if err := SetLevelByName("test-subsystem-c", ErrorLevel); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if l.Level() != ErrorLevel {
+		t.Fatalf("Level() = %v, want error", l.Level())
+	}
+
+	if err := SetLevelByName("no-such-subsystem", ErrorLevel); err == nil {
+		t.Fatalf("expected an error for an unregistered subsystem")
+	}
+}
+
+func TestSetAllLevelsDoesNotDeadlock(t *testing.T) {
+	l1 := registerTestLogger(t, "test-subsystem-d")
+	l2 := registerTestLogger(t, "test-subsystem-e")
+	defer SetLogLevel("warning")
+
+	SetAllLevels(DebugLevel)
+
+	if l1.Level() != DebugLevel || l2.Level() != DebugLevel {
+		t.Fatalf("expected every registered Logger to move to debug")
+	}
+	if GetLoggingLevel("debug") != loggingLevel {
+		t.Fatalf("expected the package-wide default to move to debug too")
+	}
+}
+
+func TestLoggerLogRespectsOwnLevel(t *testing.T) {
+	read := captureLogOutput(t)
+	l := registerTestLogger(t, "test-subsystem-f")
+	l.SetLevel(ErrorLevel)
+	SetLogLevel("debug") // global level is irrelevant once a Logger is registered
+
+	l.Infof("should be filtered by the subsystem's own level")
+	if strings.Contains(read(), "should be filtered") {
+		t.Fatalf("expected info to be filtered at the subsystem's error level, got %q", read())
+	}
+
+	l.Errorf("visible %s", "error")
+	if !strings.Contains(read(), "visible error") {
+		t.Fatalf("expected error line to be written, got %q", read())
+	}
+	if !strings.Contains(read(), "subsystem=test-subsystem-f") {
+		t.Fatalf("expected the subsystem field to be attached, got %q", read())
+	}
+}
+
+func TestLoggerForRoutesPackageLevelCallsToRegisteredLogger(t *testing.T) {
+	read := captureLogOutput(t)
+
+	// loggerFor matches on the unqualified package name, which for
+	// this test binary's package-level calls is "logging" itself.
+	l := registerTestLogger(t, "logging")
+	l.SetLevel(ErrorLevel)
+	SetLogLevel("debug")
+
+	Infof("should be filtered by the registered logging subsystem")
+	if strings.Contains(read(), "should be filtered") {
+		t.Fatalf("expected package-level Infof to be routed through the registered Logger, got %q", read())
+	}
+
+	Errorf("visible through registered logger")
+	if !strings.Contains(read(), "visible through registered logger") {
+		t.Fatalf("expected error line to be written, got %q", read())
+	}
+}
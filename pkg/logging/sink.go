@@ -0,0 +1,245 @@
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// fileSink owns the log file opened by SetLogFile. Reads/writes are
+// guarded by a mutex so Printf/Entry/Logger calls arriving
+// concurrently from CNI invocations and device plugin gRPC handlers
+// can't corrupt each other's output, and so the file can be rotated or
+// reopened (e.g. on SIGHUP) without restarting the plugin.
+type fileSink struct {
+	mu         sync.RWMutex
+	file       *os.File
+	path       string
+	size       int64
+	maxSize    int64 // bytes; 0 disables size-based rotation
+	maxBackups int
+}
+
+// sink holds the single active fileSink, or nil if SetLogFile has not
+// been called. It's an atomic.Pointer rather than a bare *fileSink
+// because SetLogFile can swap it concurrently with Printf/Entry/Logger
+// calls reading it on every write.
+var sink atomic.Pointer[fileSink]
+
+func newFileSink(path string) (*fileSink, error) {
+	fp, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := fp.Stat()
+	if err != nil {
+		fp.Close()
+		return nil, err
+	}
+
+	return &fileSink{file: fp, path: path, size: info.Size()}, nil
+}
+
+// Write appends b to the current log file, rotating first if it would
+// push the file past maxSize.
+func (s *fileSink) Write(b []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.maxSize > 0 && s.size+int64(len(b)) > s.maxSize {
+		if err := s.rotateLocked(); err != nil {
+			fmt.Fprintf(os.Stderr, "%s logging: cannot rotate %s: %s\n", pluginName, s.path, err)
+		}
+	}
+
+	n, err := s.file.Write(b)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s logging: write to %s failed: %s\n", pluginName, s.path, err)
+		return
+	}
+	s.size += int64(n)
+}
+
+// Reopen closes and reopens the log file at its current path, picking
+// up a file moved aside by external logrotate. Used by the SIGHUP
+// handler installed by WatchSIGHUP.
+func (s *fileSink) Reopen() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fp, err := os.OpenFile(s.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := fp.Stat()
+	if err != nil {
+		fp.Close()
+		return err
+	}
+
+	_ = s.file.Close()
+	s.file = fp
+	s.size = info.Size()
+	return nil
+}
+
+// SetMaxSize sets the size in bytes s's log file may reach before it
+// is rotated and gzip-compressed. 0 (the default) disables size-based
+// rotation.
+func (s *fileSink) SetMaxSize(bytes int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxSize = bytes
+}
+
+// SetMaxBackups sets how many rotated, gzip-compressed backups of s's
+// log file to retain. Older backups are deleted as new ones are made.
+func (s *fileSink) SetMaxBackups(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxBackups = n
+}
+
+// Close flushes and closes the underlying file.
+func (s *fileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// rotateLocked closes the current file, gzips it to a timestamped
+// backup, prunes old backups, and opens a fresh file at the original
+// path. s.mu must already be held.
+func (s *fileSink) rotateLocked() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+
+	backup := fmt.Sprintf("%s.%s.gz", s.path, time.Now().Format("20060102T150405.000000000"))
+	if err := gzipFile(s.path, backup); err != nil {
+		return err
+	}
+	if err := os.Remove(s.path); err != nil {
+		return err
+	}
+
+	fp, err := os.OpenFile(s.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	s.file = fp
+	s.size = 0
+
+	s.pruneBackupsLocked()
+	return nil
+}
+
+// pruneBackupsLocked deletes the oldest rotated backups beyond
+// maxBackups. s.mu must already be held.
+func (s *fileSink) pruneBackupsLocked() {
+	if s.maxBackups <= 0 {
+		return
+	}
+
+	matches, err := filepath.Glob(s.path + ".*.gz")
+	if err != nil || len(matches) <= s.maxBackups {
+		return
+	}
+
+	sort.Strings(matches) // timestamped names sort chronologically
+	for _, old := range matches[:len(matches)-s.maxBackups] {
+		_ = os.Remove(old)
+	}
+}
+
+func gzipFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	if _, err := io.Copy(gz, in); err != nil {
+		gz.Close()
+		return err
+	}
+	return gz.Close()
+}
+
+// SetMaxSize sets the size in bytes the current log file may reach
+// before it is rotated and gzip-compressed. Must be called after
+// SetLogFile. 0 (the default) disables size-based rotation.
+func SetMaxSize(bytes int64) {
+	if s := sink.Load(); s != nil {
+		s.SetMaxSize(bytes)
+	}
+}
+
+// SetMaxBackups sets how many rotated, gzip-compressed log backups to
+// retain. Must be called after SetLogFile.
+func SetMaxBackups(n int) {
+	if s := sink.Load(); s != nil {
+		s.SetMaxBackups(n)
+	}
+}
+
+// WatchSIGHUP installs a signal handler that reopens the current log
+// file on SIGHUP, so external logrotate setups can rotate the file
+// without restarting the plugin. It returns a function that stops
+// watching; callers should defer it.
+func WatchSIGHUP() func() {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-sighup:
+				if s := sink.Load(); s != nil {
+					if err := s.Reopen(); err != nil {
+						fmt.Fprintf(os.Stderr, "%s logging: cannot reopen log file: %s\n", pluginName, err)
+					}
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(sighup)
+		close(done)
+	}
+}
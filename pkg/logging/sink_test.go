@@ -0,0 +1,152 @@
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileSinkWriteAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+	s, err := newFileSink(path)
+	if err != nil {
+		t.Fatalf("newFileSink: %s", err)
+	}
+	defer s.Close()
+
+	s.Write([]byte("line one\n"))
+	s.Write([]byte("line two\n"))
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %s", err)
+	}
+	if string(b) != "line one\nline two\n" {
+		t.Fatalf("unexpected file contents: %q", b)
+	}
+}
+
+func TestFileSinkRotatesAndGzipsPastMaxSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+	s, err := newFileSink(path)
+	if err != nil {
+		t.Fatalf("newFileSink: %s", err)
+	}
+	defer s.Close()
+
+	s.SetMaxSize(10)
+	s.Write([]byte("0123456789")) // exactly fills maxSize, no rotation yet
+	s.Write([]byte("trigger"))    // pushes past maxSize, rotates first
+
+	backups, err := filepath.Glob(path + ".*.gz")
+	if err != nil {
+		t.Fatalf("Glob: %s", err)
+	}
+	if len(backups) != 1 {
+		t.Fatalf("expected 1 rotated backup, got %d: %v", len(backups), backups)
+	}
+
+	gz, err := os.Open(backups[0])
+	if err != nil {
+		t.Fatalf("cannot open backup: %s", err)
+	}
+	defer gz.Close()
+
+	r, err := gzip.NewReader(gz)
+	if err != nil {
+		t.Fatalf("cannot read gzip backup: %s", err)
+	}
+	defer r.Close()
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("cannot decompress backup: %s", err)
+	}
+	if string(b) != "0123456789" {
+		t.Fatalf("unexpected backup contents: %q", b)
+	}
+
+	b, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %s", err)
+	}
+	if string(b) != "trigger" {
+		t.Fatalf("expected only the post-rotation write in the live file, got %q", b)
+	}
+}
+
+func TestFileSinkPrunesOldBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+	s, err := newFileSink(path)
+	if err != nil {
+		t.Fatalf("newFileSink: %s", err)
+	}
+	defer s.Close()
+
+	s.SetMaxSize(1)
+	s.SetMaxBackups(2)
+	for i := 0; i < 5; i++ {
+		s.Write([]byte("xx"))
+	}
+
+	backups, err := filepath.Glob(path + ".*.gz")
+	if err != nil {
+		t.Fatalf("Glob: %s", err)
+	}
+	if len(backups) > 2 {
+		t.Fatalf("expected at most 2 backups to be retained, got %d: %v", len(backups), backups)
+	}
+}
+
+func TestFileSinkReopenPicksUpMovedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+	s, err := newFileSink(path)
+	if err != nil {
+		t.Fatalf("newFileSink: %s", err)
+	}
+	defer s.Close()
+
+	s.Write([]byte("before rotate\n"))
+	if err := os.Rename(path, path+".moved"); err != nil {
+		t.Fatalf("Rename: %s", err)
+	}
+
+	if err := s.Reopen(); err != nil {
+		t.Fatalf("Reopen: %s", err)
+	}
+	s.Write([]byte("after reopen\n"))
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %s", err)
+	}
+	if string(b) != "after reopen\n" {
+		t.Fatalf("expected a fresh file at path after Reopen, got %q", b)
+	}
+}
+
+func TestSetMaxSizeAndSetMaxBackupsAreNoOpsWithoutASink(t *testing.T) {
+	if old := sink.Swap(nil); old != nil {
+		defer func() { sink.Store(old) }()
+	}
+
+	// Must not panic when no SetLogFile has been called.
+	SetMaxSize(1024)
+	SetMaxBackups(3)
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// vLevel is the global V-level that V(n) checks against. Per-packet or
+// per-syscall debug probes in the AF_XDP fast path can be left
+// compiled in at a high V-level and only toggled on at runtime with
+// SetVLevel instead of forcing a choice between blindness and log
+// storms. The V-level is a single global value; the log level control
+// socket does not change it.
+var vLevel int32
+
+// Verbose is the result of a V-level check. It is a plain bool under
+// the hood so `if logging.V(2) { ... }` and `logging.V(2).Infof(...)`
+// both compile down to a single atomic load and branch when the level
+// is disabled.
+type Verbose bool
+
+// SetVLevel sets the global V-level that V(n) is checked against.
+func SetVLevel(level int) {
+	atomic.StoreInt32(&vLevel, int32(level))
+}
+
+// V reports whether level is enabled, returning a Verbose usable as
+// `logging.V(2).Infof(...)` or as a guard, e.g.
+// `if logging.V(3).EveryN(100) { ... }`.
+func V(level int) Verbose {
+	return Verbose(int32(level) <= atomic.LoadInt32(&vLevel))
+}
+
+// Infof logs format at info level if v is enabled.
+func (v Verbose) Infof(format string, a ...interface{}) {
+	if v {
+		Printf(InfoLevel, format, a...)
+	}
+}
+
+// Debugf logs format at debug level if v is enabled.
+func (v Verbose) Debugf(format string, a ...interface{}) {
+	if v {
+		Printf(DebugLevel, format, a...)
+	}
+}
+
+// everyNState tracks the running count of calls from one EveryN call
+// site.
+type everyNState struct {
+	mu    sync.Mutex
+	count int
+}
+
+var everyNStates sync.Map // map[string]*everyNState, keyed by call site
+
+// EveryN reports true on the 1st, (n+1)th, (2n+1)th, ... call made
+// from its call site, and false otherwise. Call sites are tracked
+// independently, so two `EveryN(100)` call sites never share a
+// counter. v must be enabled for EveryN to ever report true.
+func (v Verbose) EveryN(n int) bool {
+	if !v {
+		return false
+	}
+	if n <= 1 {
+		return true
+	}
+
+	state, _ := everyNStates.LoadOrStore(callSiteKey(2), &everyNState{})
+	s := state.(*everyNState)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	hit := s.count%n == 0
+	s.count++
+	return hit
+}
+
+// everyTState tracks the last time EveryT reported true for one call
+// site.
+type everyTState struct {
+	mu   sync.Mutex
+	last time.Time
+}
+
+var everyTStates sync.Map // map[string]*everyTState, keyed by call site
+
+// EveryT reports true at most once per d for its call site. v must be
+// enabled for EveryT to ever report true.
+func (v Verbose) EveryT(d time.Duration) bool {
+	if !v {
+		return false
+	}
+
+	state, _ := everyTStates.LoadOrStore(callSiteKey(2), &everyTState{})
+	s := state.(*everyTState)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if !s.last.IsZero() && now.Sub(s.last) < d {
+		return false
+	}
+	s.last = now
+	return true
+}
+
+var onceStates sync.Map // map[string]struct{}, keyed by call site
+
+// Once reports true only the first time it is called from its call
+// site. v must be enabled for Once to ever report true.
+func (v Verbose) Once() bool {
+	if !v {
+		return false
+	}
+
+	_, seen := onceStates.LoadOrStore(callSiteKey(2), struct{}{})
+	return !seen
+}
+
+// callSiteKey identifies the file:line skip frames up the stack from
+// here, so EveryN/EveryT/Once can track state per call site without
+// callers having to name themselves.
+func callSiteKey(skip int) string {
+	_, file, line, ok := runtime.Caller(skip)
+	if !ok {
+		return "unknown"
+	}
+	return fmt.Sprintf("%s:%d", file, line)
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2021 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVReportsEnabledLevels(t *testing.T) {
+	SetVLevel(2)
+	defer SetVLevel(0)
+
+	if !V(0) || !V(1) || !V(2) {
+		t.Fatalf("expected V(0..2) to be enabled at vLevel 2")
+	}
+	if V(3) {
+		t.Fatalf("expected V(3) to be disabled at vLevel 2")
+	}
+}
+
+func TestVerboseInfofAndDebugfRespectV(t *testing.T) {
+	read := captureLogOutput(t)
+	SetLogLevel("debug")
+	SetVLevel(1)
+	defer SetVLevel(0)
+
+	V(2).Infof("should be filtered: V too high")
+	if strings.Contains(read(), "should be filtered") {
+		t.Fatalf("expected V(2).Infof to be a no-op at vLevel 1, got %q", read())
+	}
+
+	V(1).Infof("visible info")
+	if !strings.Contains(read(), "visible info") {
+		t.Fatalf("expected V(1).Infof to log at vLevel 1, got %q", read())
+	}
+
+	V(1).Debugf("visible debug")
+	if !strings.Contains(read(), "visible debug") {
+		t.Fatalf("expected V(1).Debugf to log at vLevel 1, got %q", read())
+	}
+}
+
+func TestEveryNReportsOnTheNth(t *testing.T) {
+	SetVLevel(1)
+	defer SetVLevel(0)
+	v := V(1)
+
+	var hits int
+	for i := 0; i < 10; i++ {
+		if v.EveryN(3) {
+			hits++
+		}
+	}
+	// 1st, 4th, 7th, 10th calls hit: 4 out of 10.
+	if hits != 4 {
+		t.Fatalf("expected 4 hits out of 10 calls with EveryN(3), got %d", hits)
+	}
+}
+
+func TestEveryNDisabledWhenVIsOff(t *testing.T) {
+	v := V(5) // disabled: global vLevel defaults to 0
+	if v.EveryN(1) {
+		t.Fatalf("expected EveryN to report false when V is disabled")
+	}
+}
+
+func TestEveryNTracksCallSitesIndependently(t *testing.T) {
+	SetVLevel(1)
+	defer SetVLevel(0)
+	v := V(1)
+
+	// Two distinct call sites below must not share a counter: each
+	// should hit on its own first call.
+	if !v.EveryN(2) {
+		t.Fatalf("expected the first call from this call site to hit")
+	}
+	if !v.EveryN(2) {
+		t.Fatalf("expected the first call from this other call site to hit")
+	}
+}
+
+func TestEveryTRateLimits(t *testing.T) {
+	SetVLevel(1)
+	defer SetVLevel(0)
+	v := V(1)
+
+	// EveryT is keyed by call site (file:line), so both calls must
+	// come from the exact same line to share state.
+	var hits int
+	for i := 0; i < 2; i++ {
+		if v.EveryT(time.Hour) {
+			hits++
+		}
+	}
+	if hits != 1 {
+		t.Fatalf("expected exactly 1 hit out of 2 immediate calls from the same call site, got %d", hits)
+	}
+}
+
+func TestOnceReportsTrueOnlyOnce(t *testing.T) {
+	SetVLevel(1)
+	defer SetVLevel(0)
+	v := V(1)
+
+	// Once is keyed by call site (file:line), so both calls must come
+	// from the exact same line to share state.
+	var hits int
+	for i := 0; i < 2; i++ {
+		if v.Once() {
+			hits++
+		}
+	}
+	if hits != 1 {
+		t.Fatalf("expected exactly 1 hit out of 2 immediate calls from the same call site, got %d", hits)
+	}
+}